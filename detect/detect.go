@@ -0,0 +1,200 @@
+// Package detect guesses which supported language a piece of source code
+// is written in, so the CLI can work without an explicit --lang flag. It
+// uses the same two-stage strategy as enry: a filename/extension table
+// first, then a content-based tiebreak for extensions that are ambiguous
+// between two grammars.
+package detect
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+	tree_sitter_cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+	tree_sitter_typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+)
+
+// extensionLanguages maps a file extension to the single language it
+// implies. Extensions that are ambiguous between two supported grammars
+// (.h, .ts) are listed in ambiguousExtensions instead.
+var extensionLanguages = map[string]string{
+	".sh":   "bash",
+	".bash": "bash",
+	".c":    "c",
+	".cc":   "cpp",
+	".cpp":  "cpp",
+	".cxx":  "cpp",
+	".hpp":  "cpp",
+	".css":  "css",
+	".go":   "go",
+	".html": "html",
+	".htm":  "html",
+	".java": "java",
+	".js":   "javascript",
+	".mjs":  "javascript",
+	".cjs":  "javascript",
+	".php":  "php",
+	".py":   "python",
+	".rb":   "ruby",
+	".rs":   "rust",
+	".tsx":  "tsx",
+}
+
+// filenameLanguages maps exact (case-sensitive) base filenames to a
+// language, for the common case of extension-less source files.
+var filenameLanguages = map[string]string{
+	"Rakefile": "ruby",
+	"Gemfile":  "ruby",
+}
+
+// ambiguousExtensions lists extensions shared by more than one supported
+// grammar; DetectLanguage breaks the tie by parsing the content with each
+// candidate and keeping whichever produces fewer errors.
+var ambiguousExtensions = map[string][]string{
+	".h":  {"c", "cpp"},
+	".ts": {"typescript", "tsx"},
+}
+
+// shebangInterpreters maps the interpreter named on a "#!" line to a
+// language, matching common conventions like "#!/usr/bin/env python3".
+var shebangInterpreters = map[string]string{
+	"python3": "python",
+	"python2": "python",
+	"python":  "python",
+	"bash":    "bash",
+	"sh":      "bash",
+	"ruby":    "ruby",
+	"node":    "javascript",
+	"nodejs":  "javascript",
+	"php":     "php",
+}
+
+// DetectLanguage guesses the language of content, using path's filename
+// and extension as the primary signal and falling back to sniffing the
+// content itself (shebang lines, "<?php", ...). When the extension is
+// ambiguous between multiple supported grammars, it parses content with
+// each candidate and picks whichever has the fewest ERROR/MISSING nodes
+// weighted by the byte range they cover.
+func DetectLanguage(path string, content []byte) (string, error) {
+	candidates := candidatesForPath(path)
+
+	if len(candidates) == 0 {
+		if lang, ok := detectFromContent(content); ok {
+			candidates = []string{lang}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("could not detect a language for %q", path)
+	case 1:
+		return candidates[0], nil
+	default:
+		return pickByParsing(candidates, content)
+	}
+}
+
+func candidatesForPath(path string) []string {
+	base := filepath.Base(path)
+	if lang, ok := filenameLanguages[base]; ok {
+		return []string{lang}
+	}
+
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return nil
+	}
+	if langs, ok := ambiguousExtensions[ext]; ok {
+		return langs
+	}
+	if lang, ok := extensionLanguages[ext]; ok {
+		return []string{lang}
+	}
+	return nil
+}
+
+func detectFromContent(content []byte) (string, bool) {
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("<?php")) {
+		return "php", true
+	}
+
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return "", false
+	}
+	line := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		line = content[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return "", false
+	}
+	// "#!/usr/bin/env python3" -> last field; "#!/bin/bash" -> first field.
+	interpreter := filepath.Base(fields[len(fields)-1])
+	lang, ok := shebangInterpreters[interpreter]
+	return lang, ok
+}
+
+// candidateLanguages holds the grammars needed to break ties between
+// ambiguous extensions. It intentionally only covers those grammars (not
+// the full supportedLanguages table in package main) to keep this package
+// independent of the CLI's language registry.
+var candidateLanguages = map[string]*sitter.Language{
+	"c":          sitter.NewLanguage(tree_sitter_c.Language()),
+	"cpp":        sitter.NewLanguage(tree_sitter_cpp.Language()),
+	"typescript": sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript()),
+	"tsx":        sitter.NewLanguage(tree_sitter_typescript.LanguageTSX()),
+}
+
+func pickByParsing(candidates []string, content []byte) (string, error) {
+	type scored struct {
+		lang      string
+		errorSize uint
+	}
+
+	var scores []scored
+	for _, lang := range candidates {
+		language, ok := candidateLanguages[lang]
+		if !ok {
+			continue
+		}
+
+		parser := sitter.NewParser()
+		parser.SetLanguage(language)
+		tree := parser.Parse(content, nil)
+
+		scores = append(scores, scored{lang: lang, errorSize: errorByteSize(tree.RootNode())})
+
+		tree.Close()
+		parser.Close()
+	}
+
+	if len(scores) == 0 {
+		return "", fmt.Errorf("no parsable candidates among %s", strings.Join(candidates, ", "))
+	}
+
+	best := scores[0]
+	for _, s := range scores[1:] {
+		if s.errorSize < best.errorSize {
+			best = s
+		}
+	}
+	return best.lang, nil
+}
+
+// errorByteSize sums the byte range covered by ERROR and MISSING nodes
+// anywhere in the tree, used as a rough "how badly did this grammar parse
+// the content" score.
+func errorByteSize(node *sitter.Node) uint {
+	var total uint
+	if node.IsError() || node.IsMissing() {
+		total += node.EndByte() - node.StartByte()
+	}
+	for i := uint(0); i < node.ChildCount(); i++ {
+		total += errorByteSize(node.Child(i))
+	}
+	return total
+}
@@ -0,0 +1,102 @@
+package detect
+
+import "testing"
+
+func TestDetectLanguageByExtension(t *testing.T) {
+	cases := map[string]string{
+		"main.go":    "go",
+		"lib.rs":     "rust",
+		"app.py":     "python",
+		"script.sh":  "bash",
+		"index.js":   "javascript",
+		"styles.css": "css",
+		"page.html":  "html",
+		"Main.java":  "java",
+		"index.php":  "php",
+		"app.rb":     "ruby",
+		"thing.cpp":  "cpp",
+		"thing.tsx":  "tsx",
+	}
+
+	for path, want := range cases {
+		got, err := DetectLanguage(path, nil)
+		if err != nil {
+			t.Errorf("DetectLanguage(%q): %v", path, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDetectLanguageByFilename(t *testing.T) {
+	for _, path := range []string{"Rakefile", "Gemfile", "some/dir/Gemfile"} {
+		got, err := DetectLanguage(path, nil)
+		if err != nil {
+			t.Errorf("DetectLanguage(%q): %v", path, err)
+			continue
+		}
+		if got != "ruby" {
+			t.Errorf("DetectLanguage(%q) = %q, want ruby", path, got)
+		}
+	}
+}
+
+func TestDetectLanguageAmbiguousExtensionPicksBetterParse(t *testing.T) {
+	// A template is valid C++ but has no meaning in C, so it should parse
+	// cleanly only under the cpp grammar.
+	cppContent := []byte("template <typename T>\nT max(T a, T b) {\n  return a > b ? a : b;\n}\n")
+	lang, err := DetectLanguage("thing.h", cppContent)
+	if err != nil {
+		t.Fatalf("DetectLanguage: %v", err)
+	}
+	if lang != "cpp" {
+		t.Errorf("DetectLanguage(.h with class syntax) = %q, want cpp", lang)
+	}
+
+	cContent := []byte("struct foo { int x; };\nint bar(struct foo *f) { return f->x; }\n")
+	lang, err = DetectLanguage("thing.h", cContent)
+	if err != nil {
+		t.Fatalf("DetectLanguage: %v", err)
+	}
+	if lang != "c" {
+		t.Errorf("DetectLanguage(.h with plain C) = %q, want c", lang)
+	}
+}
+
+func TestDetectLanguageFromShebang(t *testing.T) {
+	cases := map[string]string{
+		"#!/usr/bin/env python3\nprint('hi')\n": "python",
+		"#!/bin/bash\necho hi\n":                "bash",
+		"#!/usr/bin/env node\nconsole.log(1)\n": "javascript",
+		"#!/usr/bin/ruby\nputs 1\n":             "ruby",
+	}
+
+	for content, want := range cases {
+		got, err := DetectLanguage("noext", []byte(content))
+		if err != nil {
+			t.Errorf("DetectLanguage(%q): %v", content, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestDetectLanguagePHPOpenTag(t *testing.T) {
+	got, err := DetectLanguage("noext", []byte("<?php\necho 1;\n"))
+	if err != nil {
+		t.Fatalf("DetectLanguage: %v", err)
+	}
+	if got != "php" {
+		t.Errorf("DetectLanguage(<?php) = %q, want php", got)
+	}
+}
+
+func TestDetectLanguageUnknown(t *testing.T) {
+	if _, err := DetectLanguage("README", []byte("just some text")); err == nil {
+		t.Error("DetectLanguage(unrecognized file): want error, got nil")
+	}
+}
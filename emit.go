@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Emitter renders a parsed tree or a set of query matches in one output
+// format. textEmitter preserves the tool's original human-readable output;
+// jsonEmitter and sexpEmitter make it scriptable for downstream tools
+// (linters, codemods, LLM preprocessors).
+type Emitter interface {
+	Tree(w io.Writer, node *sitter.Node, source []byte) error
+	Matches(w io.Writer, tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string) error
+}
+
+// emitterFor resolves a --format value to an Emitter, for the tree/query
+// output modes (--highlight has its own ansi/html formats, handled by the
+// highlight package instead).
+func emitterFor(format string) (Emitter, error) {
+	switch format {
+	case "", "text":
+		return textEmitter{}, nil
+	case "json":
+		return jsonEmitter{}, nil
+	case "sexp":
+		return sexpEmitter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown --format %q (want text, json or sexp)", format)
+	}
+}
+
+// textEmitter is the tool's original indented-tree / per-capture text
+// output, implemented by printTree and executeQuery.
+type textEmitter struct{}
+
+func (textEmitter) Tree(w io.Writer, node *sitter.Node, source []byte) error {
+	printTree(w, node, source, 0)
+	return nil
+}
+
+func (textEmitter) Matches(w io.Writer, tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string) error {
+	return executeQuery(w, tree, language, source, queryStr)
+}
+
+// jsonEmitter emits a stable JSON representation: one JSON object for the
+// whole tree, or one JSON object per line for query matches.
+type jsonEmitter struct{}
+
+// jsonNode mirrors a sitter.Node: kind/named/position/byte range, the field
+// name it was held under in its parent (if any), its children, and for
+// leaves, its captured text.
+type jsonNode struct {
+	Kind      string     `json:"kind"`
+	Named     bool       `json:"named"`
+	Field     string     `json:"field,omitempty"`
+	StartByte uint       `json:"start_byte"`
+	EndByte   uint       `json:"end_byte"`
+	StartRow  uint       `json:"start_row"`
+	StartCol  uint       `json:"start_col"`
+	EndRow    uint       `json:"end_row"`
+	EndCol    uint       `json:"end_col"`
+	Text      string     `json:"text,omitempty"`
+	Children  []jsonNode `json:"children,omitempty"`
+}
+
+func buildJSONNode(node *sitter.Node, source []byte, field string) jsonNode {
+	start, end := node.StartPosition(), node.EndPosition()
+	n := jsonNode{
+		Kind:      node.Kind(),
+		Named:     node.IsNamed(),
+		Field:     field,
+		StartByte: node.StartByte(),
+		EndByte:   node.EndByte(),
+		StartRow:  start.Row,
+		StartCol:  start.Column,
+		EndRow:    end.Row,
+		EndCol:    end.Column,
+	}
+
+	childCount := node.ChildCount()
+	if childCount == 0 {
+		n.Text = string(source[node.StartByte():node.EndByte()])
+		return n
+	}
+
+	n.Children = make([]jsonNode, 0, childCount)
+	for i := uint(0); i < childCount; i++ {
+		child := node.Child(i)
+		n.Children = append(n.Children, buildJSONNode(child, source, node.FieldNameForChild(uint32(i))))
+	}
+	return n
+}
+
+func (jsonEmitter) Tree(w io.Writer, node *sitter.Node, source []byte) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(buildJSONNode(node, source, ""))
+}
+
+// jsonCapture is one named capture within a jsonMatch.
+type jsonCapture struct {
+	Kind      string `json:"kind"`
+	StartByte uint   `json:"start_byte"`
+	EndByte   uint   `json:"end_byte"`
+	StartRow  uint   `json:"start_row"`
+	StartCol  uint   `json:"start_col"`
+	EndRow    uint   `json:"end_row"`
+	EndCol    uint   `json:"end_col"`
+	Text      string `json:"text"`
+}
+
+// jsonMatch is emitted once per query match, as a single JSON-lines record.
+// PatternIndex identifies which alternative in the query matched.
+// Predicates is left empty until #eq?/#match? evaluation is implemented.
+type jsonMatch struct {
+	PatternIndex int                    `json:"pattern_index"`
+	Captures     map[string]jsonCapture `json:"captures"`
+}
+
+func (jsonEmitter) Matches(w io.Writer, tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string) error {
+	q, err := sitter.NewQuery(language, queryStr)
+	if err != nil {
+		return fmt.Errorf("invalid query: %v", err)
+	}
+	defer q.Close()
+
+	qc := sitter.NewQueryCursor()
+	defer qc.Close()
+
+	enc := json.NewEncoder(w)
+	matches := qc.Matches(q, tree.RootNode(), source)
+	for {
+		match := matches.Next()
+		if match == nil {
+			break
+		}
+
+		captures := make(map[string]jsonCapture, len(match.Captures))
+		for _, capture := range match.Captures {
+			node := capture.Node
+			start, end := node.StartPosition(), node.EndPosition()
+			captures[q.CaptureNames()[capture.Index]] = jsonCapture{
+				Kind:      node.Kind(),
+				StartByte: node.StartByte(),
+				EndByte:   node.EndByte(),
+				StartRow:  start.Row,
+				StartCol:  start.Column,
+				EndRow:    end.Row,
+				EndCol:    end.Column,
+				Text:      string(source[node.StartByte():node.EndByte()]),
+			}
+		}
+
+		if err := enc.Encode(jsonMatch{PatternIndex: int(match.PatternIndex), Captures: captures}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sexpEmitter renders the tree as a compact S-expression, matching
+// `tree-sitter parse -x`: only named nodes appear, field names prefix
+// their value as "field: (...)", and there is no captured text.
+type sexpEmitter struct{}
+
+func (sexpEmitter) Tree(w io.Writer, node *sitter.Node, source []byte) error {
+	var sb strings.Builder
+	writeSexp(&sb, node, "")
+	_, err := fmt.Fprintln(w, sb.String())
+	return err
+}
+
+func writeSexp(sb *strings.Builder, node *sitter.Node, field string) {
+	if field != "" {
+		sb.WriteString(field)
+		sb.WriteString(": ")
+	}
+
+	if !node.IsNamed() {
+		sb.WriteString(node.Kind())
+		return
+	}
+
+	sb.WriteString("(")
+	sb.WriteString(node.Kind())
+	for i := uint(0); i < node.ChildCount(); i++ {
+		child := node.Child(i)
+		if !child.IsNamed() {
+			continue
+		}
+		sb.WriteString(" ")
+		writeSexp(sb, child, node.FieldNameForChild(uint32(i)))
+	}
+	sb.WriteString(")")
+}
+
+func (sexpEmitter) Matches(w io.Writer, tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string) error {
+	q, err := sitter.NewQuery(language, queryStr)
+	if err != nil {
+		return fmt.Errorf("invalid query: %v", err)
+	}
+	defer q.Close()
+
+	qc := sitter.NewQueryCursor()
+	defer qc.Close()
+
+	matches := qc.Matches(q, tree.RootNode(), source)
+	for {
+		match := matches.Next()
+		if match == nil {
+			break
+		}
+
+		var sb strings.Builder
+		for i, capture := range match.Captures {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString("@" + q.CaptureNames()[capture.Index] + " ")
+			writeSexp(&sb, &capture.Node, "")
+		}
+		if _, err := fmt.Fprintln(w, sb.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+)
+
+func parseGoForEmit(t *testing.T, source []byte) (*sitter.Language, *sitter.Tree) {
+	t.Helper()
+
+	language := sitter.NewLanguage(tree_sitter_go.Language())
+	parser := sitter.NewParser()
+	t.Cleanup(parser.Close)
+	if err := parser.SetLanguage(language); err != nil {
+		t.Fatalf("SetLanguage: %v", err)
+	}
+	tree := parser.Parse(source, nil)
+	t.Cleanup(tree.Close)
+	return language, tree
+}
+
+func TestEmitterForFormats(t *testing.T) {
+	cases := map[string]interface{}{
+		"":     textEmitter{},
+		"text": textEmitter{},
+		"json": jsonEmitter{},
+		"sexp": sexpEmitter{},
+	}
+	for format, want := range cases {
+		got, err := emitterFor(format)
+		if err != nil {
+			t.Errorf("emitterFor(%q): %v", format, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("emitterFor(%q) = %T, want %T", format, got, want)
+		}
+	}
+
+	if _, err := emitterFor("yaml"); err == nil {
+		t.Error("emitterFor(\"yaml\"): want error, got nil")
+	}
+}
+
+// TestJSONEmitterTreeFieldNames exercises buildJSONNode's child walk, which
+// calls node.FieldNameForChild with a uint32 index derived from a uint loop
+// variable.
+func TestJSONEmitterTreeFieldNames(t *testing.T) {
+	source := []byte("package main\n\nfunc greet(name string) {}\n")
+	_, tree := parseGoForEmit(t, source)
+
+	var buf bytes.Buffer
+	if err := (jsonEmitter{}).Tree(&buf, tree.RootNode(), source); err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+
+	var root jsonNode
+	if err := json.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+
+	var findField func(n jsonNode, field string) bool
+	findField = func(n jsonNode, field string) bool {
+		if n.Field == field {
+			return true
+		}
+		for _, c := range n.Children {
+			if findField(c, field) {
+				return true
+			}
+		}
+		return false
+	}
+	if !findField(root, "name") {
+		t.Error("expected a node with field \"name\" (the function_declaration's identifier) in the JSON tree")
+	}
+}
+
+func TestJSONEmitterMatches(t *testing.T) {
+	source := []byte("package main\n\nfunc greet() {}\n")
+	language, tree := parseGoForEmit(t, source)
+
+	var buf bytes.Buffer
+	err := (jsonEmitter{}).Matches(&buf, tree, language, source, "(function_declaration name: (identifier) @fn)")
+	if err != nil {
+		t.Fatalf("Matches: %v", err)
+	}
+
+	var m jsonMatch
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if m.Captures["fn"].Text != "greet" {
+		t.Errorf("captures[fn].Text = %q, want %q", m.Captures["fn"].Text, "greet")
+	}
+}
+
+// TestSexpEmitterTree exercises writeSexp's recursive FieldNameForChild call.
+func TestSexpEmitterTree(t *testing.T) {
+	source := []byte("package main\n\nfunc greet() {}\n")
+	_, tree := parseGoForEmit(t, source)
+
+	var buf bytes.Buffer
+	if err := (sexpEmitter{}).Tree(&buf, tree.RootNode(), source); err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "name:") {
+		t.Errorf("sexp output missing a field-name prefix: %q", out)
+	}
+	if !strings.Contains(out, "function_declaration") {
+		t.Errorf("sexp output missing function_declaration: %q", out)
+	}
+}
+
+// TestSexpEmitterMatches exercises the capture.Node addressability fix in
+// sexpEmitter.Matches (writeSexp needs *sitter.Node, not the value-typed
+// QueryCapture.Node).
+func TestSexpEmitterMatches(t *testing.T) {
+	source := []byte("package main\n\nfunc greet() {}\n")
+	language, tree := parseGoForEmit(t, source)
+
+	var buf bytes.Buffer
+	err := (sexpEmitter{}).Matches(&buf, tree, language, source, "(function_declaration name: (identifier) @fn)")
+	if err != nil {
+		t.Fatalf("Matches: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "@fn") || !strings.Contains(out, "identifier") {
+		t.Errorf("sexp match output = %q, want it to contain @fn and identifier", out)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ensureGrammarBuilt makes sure the shared library for entry exists in the
+// cache and is built from the pinned revision, consulting (and updating)
+// the lockfile to avoid redundant clone/compile work. It returns the path
+// to the compiled shared library.
+func ensureGrammarBuilt(cacheDir string, entry GrammarEntry, lock grammarLockfile) (string, error) {
+	grammarDir := cacheDir + "/" + entry.Name
+	soPath := grammarDir + "/" + sharedLibName(entry.Name)
+
+	if existing, ok := lock[entry.Name]; ok && existing.Revision == entry.Revision {
+		if _, err := os.Stat(cacheDir + "/" + existing.Library); err == nil {
+			return cacheDir + "/" + existing.Library, nil
+		}
+	}
+
+	srcDir := grammarDir + "/src"
+	if err := os.MkdirAll(grammarDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating grammar dir: %v", err)
+	}
+
+	if err := fetchGrammarSource(entry, grammarDir); err != nil {
+		return "", fmt.Errorf("fetching %s: %v", entry.Name, err)
+	}
+
+	if entry.Path != "" {
+		srcDir = grammarDir + "/" + entry.Path + "/src"
+	}
+
+	if err := compileGrammar(entry.Name, srcDir, soPath); err != nil {
+		return "", fmt.Errorf("compiling %s: %v", entry.Name, err)
+	}
+
+	lock[entry.Name] = grammarLock{
+		Revision: entry.Revision,
+		Library:  entry.Name + "/" + sharedLibName(entry.Name),
+	}
+
+	return soPath, nil
+}
+
+// fetchGrammarSource clones entry.URL into dir and checks out entry.Revision
+// pinned exactly, matching the way Helix pulls grammar sources.
+func fetchGrammarSource(entry GrammarEntry, dir string) error {
+	if _, err := os.Stat(dir + "/.git"); err != nil {
+		cmd := exec.Command("git", "init", "--quiet", dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git init: %v: %s", err, out)
+		}
+		cmd = exec.Command("git", "-C", dir, "remote", "add", "origin", entry.URL)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git remote add: %v: %s", err, out)
+		}
+	}
+
+	cmd := exec.Command("git", "-C", dir, "fetch", "--depth", "1", "origin", entry.Revision)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git fetch: %v: %s", err, out)
+	}
+
+	cmd = exec.Command("git", "-C", dir, "checkout", "--quiet", "FETCH_HEAD")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git checkout: %v: %s", err, out)
+	}
+
+	return nil
+}
+
+// compileGrammar builds src/parser.c (and src/scanner.c or src/scanner.cc,
+// if present) into a shared library at soPath using cc, the same approach
+// tree-sitter's own CLI uses to produce loadable parsers.
+func compileGrammar(name, srcDir, soPath string) error {
+	parserC := srcDir + "/parser.c"
+	if _, err := os.Stat(parserC); err != nil {
+		return fmt.Errorf("no src/parser.c found for %s (unsupported grammar layout?)", name)
+	}
+
+	args := []string{"-shared", "-fPIC", "-O2", "-I" + srcDir, "-o", soPath, parserC}
+
+	if _, err := os.Stat(srcDir + "/scanner.c"); err == nil {
+		args = append(args, srcDir+"/scanner.c")
+	} else if _, err := os.Stat(srcDir + "/scanner.cc"); err == nil {
+		args = append(args, srcDir+"/scanner.cc", "-lstdc++")
+	}
+
+	cmd := exec.Command("cc", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cc %v: %v: %s", args, err, out)
+	}
+
+	return nil
+}
+
+func sharedLibName(grammarName string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "libtree-sitter-" + grammarName + ".dylib"
+	default:
+		return "libtree-sitter-" + grammarName + ".so"
+	}
+}
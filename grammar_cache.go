@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// grammarCacheDir returns $XDG_CACHE_HOME/tree-sitter-debugger (or the
+// platform equivalent via os.UserCacheDir), creating it if necessary. Each
+// grammar gets a subdirectory named after it containing the cloned source
+// and the compiled shared library.
+func grammarCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving cache dir: %v", err)
+	}
+	dir := base + "/tree-sitter-debugger"
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating cache dir: %v", err)
+	}
+	return dir, nil
+}
+
+// grammarLock records the revision and ABI of the grammar that produced a
+// cached shared library, so subsequent runs can skip cloning and recompiling
+// when grammars.toml hasn't changed.
+type grammarLock struct {
+	Revision string `json:"revision"`
+	Library  string `json:"library"` // path to the compiled .so, relative to the cache dir
+}
+
+type grammarLockfile map[string]grammarLock
+
+// readGrammarLockfile loads <cacheDir>/grammars.lock.json, returning an
+// empty lockfile if it doesn't exist yet.
+func readGrammarLockfile(cacheDir string) (grammarLockfile, error) {
+	data, err := os.ReadFile(cacheDir + "/grammars.lock.json")
+	if os.IsNotExist(err) {
+		return grammarLockfile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lockfile: %v", err)
+	}
+
+	lock := grammarLockfile{}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing lockfile: %v", err)
+	}
+	return lock, nil
+}
+
+func writeGrammarLockfile(cacheDir string, lock grammarLockfile) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding lockfile: %v", err)
+	}
+	if err := os.WriteFile(cacheDir+"/grammars.lock.json", data, 0o644); err != nil {
+		return fmt.Errorf("writing lockfile: %v", err)
+	}
+	return nil
+}
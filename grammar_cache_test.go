@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGrammarLockfileRoundtrip(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	lock, err := readGrammarLockfile(cacheDir)
+	if err != nil {
+		t.Fatalf("readGrammarLockfile on empty dir: %v", err)
+	}
+	if len(lock) != 0 {
+		t.Fatalf("got %d entries for a nonexistent lockfile, want 0", len(lock))
+	}
+
+	lock["rust"] = grammarLock{Revision: "abc123", Library: "rust/libtree-sitter-rust.so"}
+	if err := writeGrammarLockfile(cacheDir, lock); err != nil {
+		t.Fatalf("writeGrammarLockfile: %v", err)
+	}
+
+	reloaded, err := readGrammarLockfile(cacheDir)
+	if err != nil {
+		t.Fatalf("readGrammarLockfile after write: %v", err)
+	}
+	if reloaded["rust"] != lock["rust"] {
+		t.Errorf("reloaded entry = %+v, want %+v", reloaded["rust"], lock["rust"])
+	}
+}
+
+func TestSharedLibName(t *testing.T) {
+	name := sharedLibName("rust")
+	if name != "libtree-sitter-rust.so" && name != "libtree-sitter-rust.dylib" {
+		t.Errorf("sharedLibName(%q) = %q, want a libtree-sitter-rust.{so,dylib} name", "rust", name)
+	}
+}
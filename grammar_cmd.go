@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runGrammarCommand implements the `grammar` subcommand, which manages the
+// dynamically loaded grammars declared in grammars.toml independently of
+// the main parse/query flow.
+func runGrammarCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: tree-sitter-debugger grammar <build|list>")
+		os.Exit(1)
+	}
+
+	configPath, err := defaultGrammarsConfigPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "build":
+		cfg, err := loadGrammarConfig(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", configPath, err)
+			os.Exit(1)
+		}
+		if _, err := loadDynamicLanguages(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error building grammars: %v\n", err)
+			os.Exit(1)
+		}
+		for _, g := range cfg.Grammar {
+			fmt.Printf("built %s @ %s\n", g.Name, g.Revision)
+		}
+	case "list":
+		cfg, err := loadGrammarConfig(configPath)
+		if os.IsNotExist(err) {
+			fmt.Println("No dynamic grammars configured (no grammars.toml found)")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", configPath, err)
+			os.Exit(1)
+		}
+		for _, g := range cfg.Grammar {
+			fmt.Printf(" - %s (%s @ %s)\n", g.Name, g.URL, g.Revision)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown grammar subcommand: %s\n", args[0])
+		fmt.Fprintln(os.Stderr, "Usage: tree-sitter-debugger grammar <build|list>")
+		os.Exit(1)
+	}
+}
+
+// mergeDynamicLanguages loads grammars.toml (if present) and merges any
+// dynamically compiled languages into the static supportedLanguages table,
+// so --lang and --list-languages treat both uniformly. A missing config
+// file is silently treated as "no dynamic grammars".
+func mergeDynamicLanguages() {
+	configPath, err := defaultGrammarsConfigPath()
+	if err != nil {
+		return
+	}
+
+	cfg, err := loadGrammarConfig(configPath)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring grammars.toml: %v\n", err)
+		return
+	}
+
+	dynamic, err := loadDynamicLanguages(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load dynamic grammars: %v\n", err)
+		return
+	}
+
+	for name, language := range dynamic {
+		supportedLanguages[name] = language
+	}
+}
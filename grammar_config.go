@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// GrammarEntry describes a single dynamically loadable grammar as declared
+// in grammars.toml. It mirrors the shape Helix and rgit use for their
+// language registries: a git source pinned to a revision, plus an optional
+// subpath for monorepo-style grammar sources (e.g. tree-sitter-typescript,
+// which holds "typescript" and "tsx" side by side).
+type GrammarEntry struct {
+	Name     string `toml:"name"`
+	URL      string `toml:"url"`
+	Revision string `toml:"revision"`
+	Path     string `toml:"path"` // subpath containing src/parser.c, if any
+}
+
+// GrammarConfig is the parsed form of grammars.toml.
+type GrammarConfig struct {
+	Grammar []GrammarEntry `toml:"grammar"`
+}
+
+// defaultGrammarsConfigPath returns the conventional location for
+// grammars.toml: $XDG_CONFIG_HOME/tree-sitter-debugger/grammars.toml, falling
+// back to os.UserConfigDir.
+func defaultGrammarsConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving config dir: %v", err)
+	}
+	return dir + "/tree-sitter-debugger/grammars.toml", nil
+}
+
+// loadGrammarConfig reads and validates a grammars.toml file. A missing file
+// is not an error; callers should check os.IsNotExist and treat it as "no
+// dynamic grammars configured".
+func loadGrammarConfig(path string) (*GrammarConfig, error) {
+	var cfg GrammarConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(cfg.Grammar))
+	for _, g := range cfg.Grammar {
+		if g.Name == "" {
+			return nil, fmt.Errorf("grammars.toml: entry missing required 'name'")
+		}
+		if g.URL == "" {
+			return nil, fmt.Errorf("grammars.toml: grammar %q missing required 'url'", g.Name)
+		}
+		if g.Revision == "" {
+			return nil, fmt.Errorf("grammars.toml: grammar %q missing required 'revision'", g.Name)
+		}
+		if seen[g.Name] {
+			return nil, fmt.Errorf("grammars.toml: duplicate grammar name %q", g.Name)
+		}
+		seen[g.Name] = true
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempToml(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "grammars.toml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("writing temp grammars.toml: %v", err)
+	}
+	return path
+}
+
+func TestLoadGrammarConfig(t *testing.T) {
+	path := writeTempToml(t, `
+[[grammar]]
+name = "zig"
+url = "https://github.com/tree-sitter-grammars/tree-sitter-zig"
+revision = "abc123"
+
+[[grammar]]
+name = "typescript"
+url = "https://github.com/tree-sitter/tree-sitter-typescript"
+revision = "def456"
+path = "tsx"
+`)
+
+	cfg, err := loadGrammarConfig(path)
+	if err != nil {
+		t.Fatalf("loadGrammarConfig: %v", err)
+	}
+	if len(cfg.Grammar) != 2 {
+		t.Fatalf("got %d entries, want 2", len(cfg.Grammar))
+	}
+	if cfg.Grammar[1].Path != "tsx" {
+		t.Errorf("Path = %q, want %q", cfg.Grammar[1].Path, "tsx")
+	}
+}
+
+func TestLoadGrammarConfigMissingFieldsRejected(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"missing name", `[[grammar]]
+url = "https://example.com/x"
+revision = "abc"
+`},
+		{"missing url", `[[grammar]]
+name = "zig"
+revision = "abc"
+`},
+		{"missing revision", `[[grammar]]
+name = "zig"
+url = "https://example.com/x"
+`},
+		{"duplicate name", `[[grammar]]
+name = "zig"
+url = "https://example.com/x"
+revision = "abc"
+
+[[grammar]]
+name = "zig"
+url = "https://example.com/y"
+revision = "def"
+`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := writeTempToml(t, c.body)
+			if _, err := loadGrammarConfig(path); err == nil {
+				t.Errorf("loadGrammarConfig(%q): want error, got nil", c.name)
+			}
+		})
+	}
+}
+
+func TestLoadGrammarConfigMissingFile(t *testing.T) {
+	_, err := loadGrammarConfig(filepath.Join(t.TempDir(), "does-not-exist.toml"))
+	if err == nil {
+		t.Fatal("want error for missing config file, got nil")
+	}
+	if !os.IsNotExist(err) {
+		t.Errorf("err = %v, want an os.IsNotExist error", err)
+	}
+}
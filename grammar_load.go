@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// loadDynamicLanguage dlopens the shared library at soPath and calls its
+// tree_sitter_<name> entry point to obtain the TSLanguage pointer, then
+// wraps it the same way the statically linked bindings do. This is the only
+// place in the program that touches cgo-free dynamic loading.
+func loadDynamicLanguage(name, soPath string) (*sitter.Language, error) {
+	handle, err := purego.Dlopen(soPath, purego.RTLD_NOW|purego.RTLD_GLOBAL)
+	if err != nil {
+		return nil, fmt.Errorf("dlopen %s: %v", soPath, err)
+	}
+
+	symbol := "tree_sitter_" + name
+	var entryPoint func() unsafe.Pointer
+	purego.RegisterLibFunc(&entryPoint, handle, symbol)
+
+	ptr := entryPoint()
+	if ptr == nil {
+		return nil, fmt.Errorf("%s: %s returned a null language", soPath, symbol)
+	}
+
+	language := sitter.NewLanguage(ptr)
+	if abi := language.AbiVersion(); abi < sitter.MIN_COMPATIBLE_LANGUAGE_VERSION || abi > sitter.LANGUAGE_VERSION {
+		// Surface this as a clean error rather than letting an incompatible
+		// ABI (most commonly an old external scanner) crash later inside
+		// cgo-adjacent parsing code.
+		return nil, fmt.Errorf("%s: unsupported grammar ABI version %d (want %d-%d)",
+			name, abi, sitter.MIN_COMPATIBLE_LANGUAGE_VERSION, sitter.LANGUAGE_VERSION)
+	}
+
+	return language, nil
+}
+
+// loadDynamicLanguages builds (if needed) and loads every grammar declared
+// in cfg, returning them keyed by name so they can be merged into
+// supportedLanguages.
+func loadDynamicLanguages(cfg *GrammarConfig) (map[string]*sitter.Language, error) {
+	cacheDir, err := grammarCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := readGrammarLockfile(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make(map[string]*sitter.Language, len(cfg.Grammar))
+	for _, entry := range cfg.Grammar {
+		soPath, err := ensureGrammarBuilt(cacheDir, entry, lock)
+		if err != nil {
+			return nil, fmt.Errorf("grammar %q: %v", entry.Name, err)
+		}
+
+		language, err := loadDynamicLanguage(entry.Name, soPath)
+		if err != nil {
+			return nil, fmt.Errorf("grammar %q: %v", entry.Name, err)
+		}
+
+		languages[entry.Name] = language
+	}
+
+	if err := writeGrammarLockfile(cacheDir, lock); err != nil {
+		return nil, err
+	}
+
+	return languages, nil
+}
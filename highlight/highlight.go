@@ -0,0 +1,161 @@
+// Package highlight renders parsed source using a tree-sitter highlight
+// query (highlights.scm), the same mechanism editors use for syntax
+// highlighting, as either ANSI-colored terminal output or HTML spans.
+package highlight
+
+import (
+	"fmt"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+type span struct {
+	start, end uint
+	capture    string
+}
+
+// owners assigns each byte in source to at most one capture name, resolving
+// overlapping captures with tree-sitter's own precedence rule: the
+// innermost (narrowest) match wins, and among matches of equal width the
+// one encountered later in query match order wins.
+func owners(tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string) ([]string, error) {
+	q, err := sitter.NewQuery(language, queryStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid highlight query: %v", err)
+	}
+	defer q.Close()
+
+	qc := sitter.NewQueryCursor()
+	defer qc.Close()
+
+	var spans []span
+	matches := qc.Matches(q, tree.RootNode(), source)
+	for {
+		match := matches.Next()
+		if match == nil {
+			break
+		}
+		for _, capture := range match.Captures {
+			spans = append(spans, span{
+				start:   capture.Node.StartByte(),
+				end:     capture.Node.EndByte(),
+				capture: q.CaptureNames()[capture.Index],
+			})
+		}
+	}
+
+	// Paint widest spans first so narrower (more specific) spans overwrite
+	// them; a stable sort keeps ties in match order, so a later match of
+	// the same width still wins, matching tree-sitter's highlight
+	// precedence rule (innermost/latest wins).
+	for i := 1; i < len(spans); i++ {
+		for j := i; j > 0 && (spans[j].end-spans[j].start) > (spans[j-1].end-spans[j-1].start); j-- {
+			spans[j], spans[j-1] = spans[j-1], spans[j]
+		}
+	}
+
+	owner := make([]string, len(source))
+	for _, s := range spans {
+		for b := s.start; b < s.end && int(b) < len(owner); b++ {
+			owner[b] = s.capture
+		}
+	}
+	return owner, nil
+}
+
+// ANSI renders source with SGR color codes applied per the theme, falling
+// back to DefaultANSITheme for any capture the given theme doesn't cover.
+func ANSI(tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string, theme Theme) (string, error) {
+	owner, err := owners(tree, language, source, queryStr)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	current := ""
+	open := false
+
+	for i, b := range source {
+		capture := owner[i]
+		if capture != current {
+			if open {
+				out.WriteString("\x1b[0m")
+				open = false
+			}
+			current = capture
+			if style, ok := styleFor(theme, DefaultANSITheme, capture); ok {
+				out.WriteString("\x1b[" + style + "m")
+				open = true
+			}
+		}
+		out.WriteByte(b)
+	}
+	if open {
+		out.WriteString("\x1b[0m")
+	}
+
+	return out.String(), nil
+}
+
+// HTML renders source as a sequence of <span class="..."> runs, one per
+// contiguous capture, with special HTML characters escaped.
+func HTML(tree *sitter.Tree, language *sitter.Language, source []byte, queryStr string, theme Theme) (string, error) {
+	owner, err := owners(tree, language, source, queryStr)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	current := ""
+	open := false
+
+	for i := 0; i < len(source); i++ {
+		capture := owner[i]
+		if capture != current {
+			if open {
+				out.WriteString("</span>")
+				open = false
+			}
+			current = capture
+			if class, ok := styleFor(theme, DefaultHTMLTheme, capture); ok {
+				out.WriteString(`<span class="` + class + `">`)
+				open = true
+			}
+		}
+		writeHTMLEscaped(&out, source[i])
+	}
+	if open {
+		out.WriteString("</span>")
+	}
+
+	return out.String(), nil
+}
+
+// styleFor looks up capture in theme, falling back to fallback when theme
+// doesn't cover it (or is nil). An empty capture (uncaptured byte) never
+// has a style.
+func styleFor(theme, fallback Theme, capture string) (string, bool) {
+	if capture == "" {
+		return "", false
+	}
+	if theme != nil {
+		if style, ok := theme.lookup(capture); ok {
+			return style, true
+		}
+	}
+	return fallback.lookup(capture)
+}
+
+func writeHTMLEscaped(out *strings.Builder, b byte) {
+	switch b {
+	case '<':
+		out.WriteString("&lt;")
+	case '>':
+		out.WriteString("&gt;")
+	case '&':
+		out.WriteString("&amp;")
+	default:
+		out.WriteByte(b)
+	}
+}
@@ -0,0 +1,101 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+)
+
+func parseGo(t *testing.T, source []byte) (*sitter.Language, *sitter.Tree) {
+	t.Helper()
+
+	language := sitter.NewLanguage(tree_sitter_go.Language())
+	parser := sitter.NewParser()
+	t.Cleanup(parser.Close)
+	if err := parser.SetLanguage(language); err != nil {
+		t.Fatalf("SetLanguage: %v", err)
+	}
+	tree := parser.Parse(source, nil)
+	t.Cleanup(tree.Close)
+	return language, tree
+}
+
+const goQuery = `
+(comment) @comment
+(function_declaration name: (identifier) @function)
+`
+
+func TestANSIColorsKnownCapture(t *testing.T) {
+	source := []byte("package main\n\nfunc main() {}\n")
+	language, tree := parseGo(t, source)
+
+	out, err := ANSI(tree, language, source, goQuery, nil)
+	if err != nil {
+		t.Fatalf("ANSI: %v", err)
+	}
+
+	if !strings.Contains(out, "\x1b[") {
+		t.Errorf("ANSI output has no escape codes: %q", out)
+	}
+	if !strings.Contains(out, "main") {
+		t.Errorf("ANSI output lost source text: %q", out)
+	}
+}
+
+func TestHTMLEscapesAndWrapsKnownCapture(t *testing.T) {
+	source := []byte("package main\n\nfunc main() {}\n")
+	language, tree := parseGo(t, source)
+
+	out, err := HTML(tree, language, source, goQuery, nil)
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+
+	if !strings.Contains(out, `<span class="hl-function">`) {
+		t.Errorf("HTML output missing function span: %q", out)
+	}
+}
+
+func TestHTMLEscapesSpecialCharacters(t *testing.T) {
+	source := []byte("package main\n\n// a < b && b > a\nfunc main() {}\n")
+	language, tree := parseGo(t, source)
+
+	out, err := HTML(tree, language, source, goQuery, nil)
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+
+	if strings.Contains(out, "a < b") {
+		t.Errorf("HTML output contains unescaped '<': %q", out)
+	}
+	if !strings.Contains(out, "a &lt; b &amp;&amp; b &gt; a") {
+		t.Errorf("HTML output did not escape comment text as expected: %q", out)
+	}
+}
+
+func TestOwnersInnermostWins(t *testing.T) {
+	// The call `foo()` is covered by both a wide (call_expression) and a
+	// narrow (identifier) capture; the narrower one must win for the bytes
+	// they share.
+	source := []byte("package main\nfunc main() { foo() }\n")
+	language, tree := parseGo(t, source)
+
+	query := `
+(call_expression) @call
+(call_expression function: (identifier) @function)
+`
+	owner, err := owners(tree, language, source, query)
+	if err != nil {
+		t.Fatalf("owners: %v", err)
+	}
+
+	idx := strings.Index(string(source), "foo")
+	if idx < 0 {
+		t.Fatal("test source missing foo()")
+	}
+	if owner[idx] != "function" {
+		t.Errorf("owner of %q byte = %q, want %q (innermost capture should win)", "foo", owner[idx], "function")
+	}
+}
@@ -0,0 +1,86 @@
+package highlight
+
+import "github.com/BurntSushi/toml"
+
+// Theme maps tree-sitter highlight capture names (without the leading "@",
+// e.g. "function", "string.special") to an output-specific value: an ANSI
+// SGR parameter string for --format=text, or a CSS class name for
+// --format=html.
+type Theme map[string]string
+
+// DefaultANSITheme is used when no --theme file is given and output is
+// going to a terminal.
+var DefaultANSITheme = Theme{
+	"comment":               "2;37",
+	"string":                "32",
+	"string.special":        "32",
+	"number":                "33",
+	"constant.builtin":      "33",
+	"keyword":               "35",
+	"function":              "34",
+	"function.method":       "34",
+	"type":                  "36",
+	"property":              "36",
+	"variable":              "0",
+	"operator":              "0",
+	"punctuation.bracket":   "0",
+	"punctuation.delimiter": "0",
+}
+
+// DefaultHTMLTheme maps captures to CSS class names of the form
+// "hl-<capture>" when no --theme file is given for --format=html.
+var DefaultHTMLTheme = Theme{
+	"comment":               "hl-comment",
+	"string":                "hl-string",
+	"string.special":        "hl-string",
+	"number":                "hl-number",
+	"constant.builtin":      "hl-constant",
+	"keyword":               "hl-keyword",
+	"function":              "hl-function",
+	"function.method":       "hl-function",
+	"type":                  "hl-type",
+	"property":              "hl-property",
+	"variable":              "hl-variable",
+	"operator":              "hl-operator",
+	"punctuation.bracket":   "hl-punctuation",
+	"punctuation.delimiter": "hl-punctuation",
+}
+
+// LoadTheme reads a TOML theme file mapping capture name to style value,
+// e.g.:
+//
+//	comment = "2;37"
+//	string = "32"
+//	keyword = "35"
+func LoadTheme(path string) (Theme, error) {
+	theme := Theme{}
+	if _, err := toml.DecodeFile(path, &theme); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// lookup finds the most specific entry in the theme for a capture name,
+// falling back from "function.method" to "function" to "" the same way
+// tree-sitter themes conventionally do.
+func (t Theme) lookup(capture string) (string, bool) {
+	for {
+		if style, ok := t[capture]; ok {
+			return style, true
+		}
+		i := lastDot(capture)
+		if i < 0 {
+			return "", false
+		}
+		capture = capture[:i]
+	}
+}
+
+func lastDot(s string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '.' {
+			return i
+		}
+	}
+	return -1
+}
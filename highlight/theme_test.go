@@ -0,0 +1,49 @@
+package highlight
+
+import "testing"
+
+func TestThemeLookupFallsBackToBaseCapture(t *testing.T) {
+	theme := Theme{"function": "34"}
+
+	style, ok := theme.lookup("function.method")
+	if !ok {
+		t.Fatal("lookup(function.method): want fallback match, got none")
+	}
+	if style != "34" {
+		t.Errorf("style = %q, want %q", style, "34")
+	}
+}
+
+func TestThemeLookupNoMatch(t *testing.T) {
+	theme := Theme{"function": "34"}
+
+	if _, ok := theme.lookup("keyword"); ok {
+		t.Error("lookup(keyword): want no match, got one")
+	}
+}
+
+func TestStyleForPrefersGivenThemeOverFallback(t *testing.T) {
+	theme := Theme{"keyword": "99"}
+	fallback := Theme{"keyword": "35"}
+
+	style, ok := styleFor(theme, fallback, "keyword")
+	if !ok || style != "99" {
+		t.Errorf("styleFor = (%q, %v), want (99, true)", style, ok)
+	}
+}
+
+func TestStyleForUsesFallbackWhenThemeMissesCapture(t *testing.T) {
+	theme := Theme{"keyword": "99"}
+	fallback := Theme{"string": "32"}
+
+	style, ok := styleFor(theme, fallback, "string")
+	if !ok || style != "32" {
+		t.Errorf("styleFor = (%q, %v), want (32, true)", style, ok)
+	}
+}
+
+func TestStyleForEmptyCaptureHasNoStyle(t *testing.T) {
+	if _, ok := styleFor(DefaultANSITheme, DefaultANSITheme, ""); ok {
+		t.Error("styleFor(\"\"): want no style for an uncaptured byte")
+	}
+}
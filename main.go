@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -23,6 +24,11 @@ import (
 	tree_sitter_ruby "github.com/tree-sitter/tree-sitter-ruby/bindings/go"
 	tree_sitter_rust "github.com/tree-sitter/tree-sitter-rust/bindings/go"
 	tree_sitter_typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+
+	"github.com/meain/tree-sitter-debugger/detect"
+	"github.com/meain/tree-sitter-debugger/highlight"
+	"github.com/meain/tree-sitter-debugger/queries"
+	"github.com/meain/tree-sitter-debugger/split"
 )
 
 var supportedLanguages = map[string]*sitter.Language{
@@ -46,13 +52,30 @@ var supportedLanguages = map[string]*sitter.Language{
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "grammar" {
+		runGrammarCommand(os.Args[2:])
+		return
+	}
+
 	var (
-		lang          = flag.String("lang", "", "Language to parse (required)")
+		lang          = flag.String("lang", "", "Language to parse (auto-detected with --auto if omitted)")
 		query         = flag.String("query", "", "Tree-sitter query to execute")
 		listLanguages = flag.Bool("list-languages", false, "List all supported languages")
+		splitMode     = flag.Bool("split", false, "Split the input into LLM-sized chunks instead of printing the tree")
+		splitMaxBytes = flag.Int("split-max-bytes", 1024, "Maximum size of each chunk produced by --split, in bytes")
+		splitOverlap  = flag.Int("split-overlap", 0, "Bytes of trailing context to repeat at the start of each chunk produced by --split")
+		splitFormat   = flag.String("split-format", "text", "Output format for --split: text or json")
+		auto          = flag.Bool("auto", false, "Detect the language from the filename and content instead of requiring --lang")
+		highlightMode = flag.Bool("highlight", false, "Render the input with syntax highlighting instead of printing the tree")
+		format        = flag.String("format", "text", "Output format: text (default), json or sexp; ansi or html with --highlight")
+		theme         = flag.String("theme", "", "TOML theme file mapping capture names to ANSI codes or CSS classes, for --highlight")
+		replMode      = flag.Bool("repl", false, "Start an interactive incremental-parsing REPL instead of a one-shot parse")
+		jsonRPC       = flag.Bool("json-rpc", false, "With --repl, frame commands as JSON-RPC requests/responses over stdio")
 	)
 	flag.Parse()
 
+	mergeDynamicLanguages()
+
 	// Check if we just need to list languages
 	if *listLanguages {
 		fmt.Println("Supported languages:")
@@ -62,19 +85,12 @@ func main() {
 		os.Exit(0)
 	}
 
-	if *lang == "" {
-		fmt.Fprintf(os.Stderr, "Error: --lang is required\n")
+	if *lang == "" && !*auto {
+		fmt.Fprintf(os.Stderr, "Error: --lang is required (or pass --auto to detect it)\n")
 		fmt.Fprintf(os.Stderr, "Use --list-languages to see all supported languages\n")
 		os.Exit(1)
 	}
 
-	language, ok := supportedLanguages[*lang]
-	if !ok {
-		fmt.Fprintf(os.Stderr, "Error: unsupported language '%s'\n", *lang)
-		fmt.Fprintf(os.Stderr, "Supported languages: %s\n", strings.Join(getSupportedLanguages(), ", "))
-		os.Exit(1)
-	}
-
 	// Handle positional arguments for filename
 	args := flag.Args()
 
@@ -95,27 +111,186 @@ func main() {
 		}
 	}
 
+	langName := *lang
+	if *auto {
+		detected, err := detect.DetectLanguage(filenameForDetection(args), input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error detecting language: %v\n", err)
+			os.Exit(1)
+		}
+		langName = detected
+	}
+
+	language, ok := supportedLanguages[langName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unsupported language '%s'\n", langName)
+		fmt.Fprintf(os.Stderr, "Supported languages: %s\n", strings.Join(getSupportedLanguages(), ", "))
+		os.Exit(1)
+	}
+
 	// Parse the code
 	parser := sitter.NewParser()
 	defer parser.Close()
 	parser.SetLanguage(language)
 
 	tree := parser.Parse(input, nil)
+
+	if *replMode {
+		final := runREPL(parser, tree, language, input, *jsonRPC)
+		final.Close()
+		return
+	}
 	defer tree.Close()
 
-	if *query != "" {
-		// Execute query
-		if err := executeQuery(tree, language, input, *query); err != nil {
-			fmt.Fprintf(os.Stderr, "Error executing query: %v\n", err)
+	switch {
+	case *splitMode:
+		if err := runSplit(tree, input, *splitMaxBytes, *splitOverlap, *splitFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error splitting: %v\n", err)
+			os.Exit(1)
+		}
+	case *highlightMode:
+		highlightFormat := *format
+		if highlightFormat == "" || highlightFormat == "text" {
+			highlightFormat = "ansi"
+		}
+		if err := runHighlight(tree, language, input, langName, highlightFormat, *theme); err != nil {
+			fmt.Fprintf(os.Stderr, "Error highlighting: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		emitter, err := emitterFor(*format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *query != "" {
+			err = emitter.Matches(os.Stdout, tree, language, input, *query)
+		} else {
+			err = emitter.Tree(os.Stdout, tree.RootNode(), input)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-	} else {
-		// Print the tree
-		printTree(tree.RootNode(), input, 0)
 	}
 }
 
+// queryLanguageNames maps the language aliases accepted by --lang (js, py,
+// ts) to the canonical name used for the bundled queries/<lang>/ directory.
+var queryLanguageNames = map[string]string{
+	"js": "javascript",
+	"py": "python",
+	"ts": "typescript",
+}
+
+func queryLanguageName(lang string) string {
+	if canonical, ok := queryLanguageNames[lang]; ok {
+		return canonical
+	}
+	return lang
+}
+
+// runHighlight renders tree/input using the bundled highlights.scm for
+// langName and writes the result to stdout in the requested format.
+func runHighlight(tree *sitter.Tree, language *sitter.Language, input []byte, langName, format, themePath string) error {
+	queryStr, ok := queries.Load(queryLanguageName(langName))
+	if !ok {
+		return fmt.Errorf("no bundled highlight query for language %q", langName)
+	}
+
+	switch format {
+	case "ansi", "":
+		theme := highlight.DefaultANSITheme
+		if themePath != "" {
+			loaded, err := highlight.LoadTheme(themePath)
+			if err != nil {
+				return fmt.Errorf("loading theme: %v", err)
+			}
+			theme = loaded
+		}
+		rendered, err := highlight.ANSI(tree, language, input, queryStr, theme)
+		if err != nil {
+			return err
+		}
+		fmt.Println(rendered)
+	case "html":
+		theme := highlight.DefaultHTMLTheme
+		if themePath != "" {
+			loaded, err := highlight.LoadTheme(themePath)
+			if err != nil {
+				return fmt.Errorf("loading theme: %v", err)
+			}
+			theme = loaded
+		}
+		rendered, err := highlight.HTML(tree, language, input, queryStr, theme)
+		if err != nil {
+			return err
+		}
+		fmt.Println(rendered)
+	default:
+		return fmt.Errorf("unknown --format %q (want ansi or html)", format)
+	}
+
+	return nil
+}
+
+// runSplit chunks tree/input for LLM consumption and writes the result to
+// stdout in the requested format.
+func runSplit(tree *sitter.Tree, input []byte, maxBytes, overlap int, format string) error {
+	chunks := split.Split(tree.RootNode(), input, split.Options{
+		Max:     maxBytes,
+		Overlap: overlap,
+	})
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		for _, c := range chunks {
+			if err := enc.Encode(splitChunkJSON{
+				Kind:      c.Kind,
+				Path:      c.Path,
+				StartByte: c.StartByte,
+				EndByte:   c.EndByte,
+				StartRow:  c.StartRow,
+				StartCol:  c.StartCol,
+				EndRow:    c.EndRow,
+				EndCol:    c.EndCol,
+				Text:      string(c.Text),
+			}); err != nil {
+				return err
+			}
+		}
+	case "text":
+		for i, c := range chunks {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("--- chunk %d: %s [%d:%d]-[%d:%d] (%d bytes) ---\n",
+				i+1, c.Path, c.StartRow+1, c.StartCol, c.EndRow+1, c.EndCol, c.EndByte-c.StartByte)
+			fmt.Println(string(c.Text))
+		}
+	default:
+		return fmt.Errorf("unknown --split-format %q (want text or json)", format)
+	}
+
+	return nil
+}
+
+// splitChunkJSON is the JSON-lines shape emitted by --split-format=json.
+type splitChunkJSON struct {
+	Kind      string `json:"kind"`
+	Path      string `json:"path"`
+	StartByte uint   `json:"start_byte"`
+	EndByte   uint   `json:"end_byte"`
+	StartRow  uint   `json:"start_row"`
+	StartCol  uint   `json:"start_col"`
+	EndRow    uint   `json:"end_row"`
+	EndCol    uint   `json:"end_col"`
+	Text      string `json:"text"`
+}
+
 func executeQuery(
+	w io.Writer,
 	tree *sitter.Tree,
 	language *sitter.Language,
 	source []byte,
@@ -141,7 +316,7 @@ func executeQuery(
 
 		matchCount++
 		if matchCount > 1 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 
 		for _, capture := range match.Captures {
@@ -151,10 +326,10 @@ func executeQuery(
 			startPoint := node.StartPosition()
 			endPoint := node.EndPosition()
 
-			fmt.Printf("@%s\n", captureName)
-			fmt.Printf("start: %d:%d\n", startPoint.Row+1, startPoint.Column)
-			fmt.Printf("end: %d:%d\n", endPoint.Row+1, endPoint.Column)
-			fmt.Printf("content:\n")
+			fmt.Fprintf(w, "@%s\n", captureName)
+			fmt.Fprintf(w, "start: %d:%d\n", startPoint.Row+1, startPoint.Column)
+			fmt.Fprintf(w, "end: %d:%d\n", endPoint.Row+1, endPoint.Column)
+			fmt.Fprintf(w, "content:\n")
 
 			// Extract the content
 			content := source[node.StartByte():node.EndByte()]
@@ -162,20 +337,20 @@ func executeQuery(
 			// Print each line with some indentation for readability
 			scanner := bufio.NewScanner(strings.NewReader(string(content)))
 			for scanner.Scan() {
-				fmt.Printf("%s\n", scanner.Text())
+				fmt.Fprintf(w, "%s\n", scanner.Text())
 			}
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 	}
 
 	if matchCount == 0 {
-		fmt.Println("No matches found")
+		fmt.Fprintln(w, "No matches found")
 	}
 
 	return nil
 }
 
-func printTree(node *sitter.Node, source []byte, depth int) {
+func printTree(w io.Writer, node *sitter.Node, source []byte, depth int) {
 	indent := strings.Repeat("  ", depth)
 	nodeType := node.Kind()
 
@@ -189,26 +364,36 @@ func printTree(node *sitter.Node, source []byte, depth int) {
 			if len(displayContent) > 50 {
 				displayContent = displayContent[:47] + "..."
 			}
-			fmt.Printf("%s(%s \"%s\")\n", indent, nodeType, displayContent)
+			fmt.Fprintf(w, "%s(%s \"%s\")\n", indent, nodeType, displayContent)
 		} else {
-			fmt.Printf("%s(%s\n", indent, nodeType)
+			fmt.Fprintf(w, "%s(%s\n", indent, nodeType)
 		}
 	} else {
 		// Anonymous node
 		content := source[node.StartByte():node.EndByte()]
 		displayContent := strings.ReplaceAll(string(content), "\n", "\\n")
 		displayContent = strings.ReplaceAll(displayContent, "\t", "\\t")
-		fmt.Printf("%s\"%s\"\n", indent, displayContent)
+		fmt.Fprintf(w, "%s\"%s\"\n", indent, displayContent)
 	}
 
 	for i := uint(0); i < node.ChildCount(); i++ {
 		child := node.Child(i)
-		printTree(child, source, depth+1)
+		printTree(w, child, source, depth+1)
 	}
 
 	if node.IsNamed() && node.ChildCount() > 0 {
-		fmt.Printf("%s)\n", indent)
+		fmt.Fprintf(w, "%s)\n", indent)
+	}
+}
+
+// filenameForDetection returns the path --auto should use for filename-based
+// detection, or "" when reading from stdin (in which case detect.DetectLanguage
+// falls back to content sniffing alone).
+func filenameForDetection(args []string) string {
+	if len(args) == 0 {
+		return ""
 	}
+	return args[0]
 }
 
 func getSupportedLanguages() []string {
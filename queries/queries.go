@@ -0,0 +1,19 @@
+// Package queries embeds the tree-sitter highlight queries bundled with
+// the debugger, one highlights.scm per supported language, so --highlight
+// works out of the box without needing queries installed on disk.
+package queries
+
+import "embed"
+
+//go:embed */highlights.scm
+var Highlights embed.FS
+
+// Load returns the embedded highlights.scm source for lang, and false if no
+// query is bundled for that language.
+func Load(lang string) (string, bool) {
+	data, err := Highlights.ReadFile(lang + "/highlights.scm")
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,128 @@
+package queries
+
+import (
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tsbash "github.com/tree-sitter/tree-sitter-bash/bindings/go"
+	tsc "github.com/tree-sitter/tree-sitter-c/bindings/go"
+	tscpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+	tscss "github.com/tree-sitter/tree-sitter-css/bindings/go"
+	tsgo "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	tshtml "github.com/tree-sitter/tree-sitter-html/bindings/go"
+	tsjava "github.com/tree-sitter/tree-sitter-java/bindings/go"
+	tsjavascript "github.com/tree-sitter/tree-sitter-javascript/bindings/go"
+	tsphp "github.com/tree-sitter/tree-sitter-php/bindings/go"
+	tspython "github.com/tree-sitter/tree-sitter-python/bindings/go"
+	tsruby "github.com/tree-sitter/tree-sitter-ruby/bindings/go"
+	tsrust "github.com/tree-sitter/tree-sitter-rust/bindings/go"
+	tstypescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+)
+
+// languageFor returns the statically linked grammar for each language this
+// package bundles a highlights.scm for, so TestBundledQueriesCompile can
+// check every query against the real node types it will run against.
+var languageFor = map[string]func() *sitter.Language{
+	"bash":       func() *sitter.Language { return sitter.NewLanguage(tsbash.Language()) },
+	"c":          func() *sitter.Language { return sitter.NewLanguage(tsc.Language()) },
+	"cpp":        func() *sitter.Language { return sitter.NewLanguage(tscpp.Language()) },
+	"css":        func() *sitter.Language { return sitter.NewLanguage(tscss.Language()) },
+	"go":         func() *sitter.Language { return sitter.NewLanguage(tsgo.Language()) },
+	"html":       func() *sitter.Language { return sitter.NewLanguage(tshtml.Language()) },
+	"java":       func() *sitter.Language { return sitter.NewLanguage(tsjava.Language()) },
+	"javascript": func() *sitter.Language { return sitter.NewLanguage(tsjavascript.Language()) },
+	"php":        func() *sitter.Language { return sitter.NewLanguage(tsphp.LanguagePHP()) },
+	"python":     func() *sitter.Language { return sitter.NewLanguage(tspython.Language()) },
+	"ruby":       func() *sitter.Language { return sitter.NewLanguage(tsruby.Language()) },
+	"rust":       func() *sitter.Language { return sitter.NewLanguage(tsrust.Language()) },
+	"typescript": func() *sitter.Language { return sitter.NewLanguage(tstypescript.LanguageTypescript()) },
+	"tsx":        func() *sitter.Language { return sitter.NewLanguage(tstypescript.LanguageTSX()) },
+}
+
+// TestBundledQueriesCompile guards against bundled highlights.scm files
+// referencing node types that don't exist in the grammar version actually
+// pinned in go.mod (easy to get wrong when a query is copied from a
+// different grammar release than the one vendored here).
+func TestBundledQueriesCompile(t *testing.T) {
+	for lang, newLanguage := range languageFor {
+		t.Run(lang, func(t *testing.T) {
+			queryStr, ok := Load(lang)
+			if !ok {
+				t.Fatalf("no bundled highlights.scm for %q", lang)
+			}
+
+			q, err := sitter.NewQuery(newLanguage(), queryStr)
+			if err != nil {
+				t.Fatalf("invalid highlights.scm for %q: %v", lang, err)
+			}
+			q.Close()
+		})
+	}
+}
+
+// TestBundledQueriesProduceCaptures smoke-tests each bundled query against a
+// small real source snippet: a query that compiles but never matches
+// anything would still leave --highlight output silently uncolored.
+func TestBundledQueriesProduceCaptures(t *testing.T) {
+	samples := map[string]string{
+		"bash":       "function greet() {\n  local name=\"$1\"\n  if [ -z \"$name\" ]; then\n    return 1\n  fi\n}\n",
+		"c":          "int main() {\n  // comment\n  return 0;\n}\n",
+		"cpp":        "class Foo {\npublic:\n  int bar() { return this->x; }\n};\n",
+		"css":        "@media screen {\n  .foo { color: #fff; }\n}\n",
+		"go":         "package main\nfunc main() {\n  println(\"hi\")\n}\n",
+		"html":       "<!doctype html>\n<html><body><p>hi</p></body></html>\n",
+		"java":       "class Foo {\n  public void bar() {\n    if (this.x) { return; }\n  }\n}\n",
+		"javascript": "class Foo {\n  bar() {\n    if (this.x) { return super.bar(); }\n  }\n}\n",
+		"php":        "<?php\nclass Foo {\n  public function bar() {\n    return $this->x;\n  }\n}\n",
+		"python":     "def greet(name):\n    # say hi\n    return f\"hi {name}\"\n",
+		"ruby":       "class Foo\n  def bar\n    :sym if true\n  end\nend\n",
+		"rust":       "struct Foo { x: i32 }\nimpl Foo {\n  fn bar(&mut self) -> i32 { crate::baz(); self.x }\n}\n",
+		"typescript": "class Foo {\n  bar(): number {\n    return this.x;\n  }\n}\n",
+		"tsx":        "function Foo() {\n  return <div className=\"x\">{this.props.x}</div>;\n}\n",
+	}
+
+	for lang, newLanguage := range languageFor {
+		t.Run(lang, func(t *testing.T) {
+			source, ok := samples[lang]
+			if !ok {
+				t.Fatalf("no sample source for %q", lang)
+			}
+			queryStr, ok := Load(lang)
+			if !ok {
+				t.Fatalf("no bundled highlights.scm for %q", lang)
+			}
+
+			language := newLanguage()
+			parser := sitter.NewParser()
+			defer parser.Close()
+			if err := parser.SetLanguage(language); err != nil {
+				t.Fatalf("SetLanguage: %v", err)
+			}
+			tree := parser.Parse([]byte(source), nil)
+			defer tree.Close()
+
+			q, err := sitter.NewQuery(language, queryStr)
+			if err != nil {
+				t.Fatalf("invalid highlights.scm for %q: %v", lang, err)
+			}
+			defer q.Close()
+
+			qc := sitter.NewQueryCursor()
+			defer qc.Close()
+
+			captures := 0
+			matches := qc.Matches(q, tree.RootNode(), []byte(source))
+			for {
+				match := matches.Next()
+				if match == nil {
+					break
+				}
+				captures += len(match.Captures)
+			}
+
+			if captures == 0 {
+				t.Errorf("highlights.scm for %q produced zero captures against sample source", lang)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// replSession keeps a parser/tree/source triple alive across commands so
+// edits can be applied incrementally instead of reparsing from scratch
+// every time. parser and the initial tree are owned by the caller (main);
+// replSession only takes ownership of trees it creates itself via reparse,
+// closing the superseded one each time.
+type replSession struct {
+	parser   *sitter.Parser
+	language *sitter.Language
+	tree     *sitter.Tree
+	source   []byte
+}
+
+// runREPL drives an interactive loop over stdin implementing the commands
+// described in the package's --repl flag help: edit, print, query, node
+// and reparse. tree and parser must outlive this call. reparse replaces
+// s.tree with a new tree and closes the one it supersedes, so runREPL
+// returns whichever tree is current when the loop ends; the caller (not
+// runREPL) owns closing it.
+func runREPL(parser *sitter.Parser, tree *sitter.Tree, language *sitter.Language, source []byte, jsonRPC bool) *sitter.Tree {
+	session := &replSession{parser: parser, language: language, tree: tree, source: source}
+
+	if jsonRPC {
+		session.runJSONRPC()
+	} else {
+		session.runText()
+	}
+
+	return session.tree
+}
+
+func (s *replSession) runText() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		result, err := s.dispatch(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+		if result != "" {
+			fmt.Println(result)
+		}
+	}
+}
+
+// dispatch parses a single REPL command line and executes it, returning
+// text to print (possibly empty, e.g. after a successful edit).
+func (s *replSession) dispatch(line string) (string, error) {
+	fields := strings.SplitN(line, " ", 2)
+	cmd := fields[0]
+	var rest string
+	if len(fields) > 1 {
+		rest = fields[1]
+	}
+
+	switch cmd {
+	case "edit":
+		return "", s.handleEditLine(rest)
+	case "reparse":
+		s.reparse()
+		return "", nil
+	case "print":
+		var sb strings.Builder
+		printTree(&sb, s.tree.RootNode(), s.source, 0)
+		return strings.TrimRight(sb.String(), "\n"), nil
+	case "query":
+		var sb strings.Builder
+		if err := executeQuery(&sb, s.tree, s.language, s.source, rest); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(sb.String(), "\n"), nil
+	case "node":
+		return s.handleNodeLine(rest)
+	default:
+		return "", fmt.Errorf("unknown command %q (want edit|print|query|node|reparse)", cmd)
+	}
+}
+
+// handleEditLine parses "<start_byte> <old_end_byte> <new_end_byte> <text>"
+// and applies it.
+func (s *replSession) handleEditLine(rest string) error {
+	fields := strings.SplitN(rest, " ", 4)
+	if len(fields) < 4 {
+		return fmt.Errorf("usage: edit <start_byte> <old_end_byte> <new_end_byte> <text>")
+	}
+
+	startByte, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid start_byte: %v", err)
+	}
+	oldEndByte, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid old_end_byte: %v", err)
+	}
+	newEndByte, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid new_end_byte: %v", err)
+	}
+	text := fields[3]
+
+	return s.edit(uint(startByte), uint(oldEndByte), uint(newEndByte), text)
+}
+
+// edit replaces source[startByte:oldEndByte] with text (whose length
+// should equal newEndByte-startByte) and records the corresponding
+// sitter.Edit against the current tree so the next reparse can proceed
+// incrementally.
+func (s *replSession) edit(startByte, oldEndByte, newEndByte uint, text string) error {
+	if oldEndByte > uint(len(s.source)) || startByte > oldEndByte {
+		return fmt.Errorf("edit range [%d, %d) out of bounds for %d-byte source", startByte, oldEndByte, len(s.source))
+	}
+	if newEndByte < startByte || uint(len(text)) != newEndByte-startByte {
+		return fmt.Errorf("text length %d does not match new_end_byte-start_byte (%d)", len(text), newEndByte-startByte)
+	}
+
+	startPoint := pointForByte(s.source, startByte)
+	oldEndPoint := pointForByte(s.source, oldEndByte)
+
+	newSource := make([]byte, 0, len(s.source)-int(oldEndByte-startByte)+len(text))
+	newSource = append(newSource, s.source[:startByte]...)
+	newSource = append(newSource, text...)
+	newSource = append(newSource, s.source[oldEndByte:]...)
+
+	newEndPoint := pointForByte(newSource, newEndByte)
+
+	s.tree.Edit(&sitter.InputEdit{
+		StartByte:      startByte,
+		OldEndByte:     oldEndByte,
+		NewEndByte:     newEndByte,
+		StartPosition:  startPoint,
+		OldEndPosition: oldEndPoint,
+		NewEndPosition: newEndPoint,
+	})
+
+	s.source = newSource
+	return nil
+}
+
+// reparse re-parses the current source incrementally against the edits
+// accumulated on the current tree, replacing it with the result.
+func (s *replSession) reparse() {
+	newTree := s.parser.Parse(s.source, s.tree)
+	s.tree.Close()
+	s.tree = newTree
+}
+
+func (s *replSession) handleNodeLine(rest string) (string, error) {
+	fields := strings.Fields(rest)
+	if len(fields) != 2 {
+		return "", fmt.Errorf("usage: node <row> <col>")
+	}
+	row, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid row: %v", err)
+	}
+	col, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid col: %v", err)
+	}
+
+	node := s.tree.RootNode().DescendantForPointRange(
+		sitter.Point{Row: uint(row), Column: uint(col)},
+		sitter.Point{Row: uint(row), Column: uint(col)},
+	)
+	if node == nil {
+		return "", fmt.Errorf("no node at %d:%d", row, col)
+	}
+
+	start, end := node.StartPosition(), node.EndPosition()
+	return fmt.Sprintf("%s [%d:%d]-[%d:%d]", node.Kind(), start.Row, start.Column, end.Row, end.Column), nil
+}
+
+// pointForByte computes the row/column of byteOffset within source, used to
+// fill in the Point fields an InputEdit needs alongside raw byte offsets.
+func pointForByte(source []byte, byteOffset uint) sitter.Point {
+	var row, col uint
+	for i := uint(0); i < byteOffset && int(i) < len(source); i++ {
+		if source[i] == '\n' {
+			row++
+			col = 0
+		} else {
+			col++
+		}
+	}
+	return sitter.Point{Row: row, Column: col}
+}
+
+// --- JSON-RPC framing -------------------------------------------------
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  string          `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type editParams struct {
+	StartByte  uint   `json:"start_byte"`
+	OldEndByte uint   `json:"old_end_byte"`
+	NewEndByte uint   `json:"new_end_byte"`
+	Text       string `json:"text"`
+}
+
+type queryParams struct {
+	Pattern string `json:"pattern"`
+}
+
+type nodeParams struct {
+	Row uint `json:"row"`
+	Col uint `json:"col"`
+}
+
+// runJSONRPC frames the same edit/print/query/node/reparse commands as
+// newline-delimited JSON-RPC 2.0 requests and responses, so the REPL can be
+// driven by editors/LSP-adjacent tooling instead of a human typing commands.
+func (s *replSession) runJSONRPC() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(os.Stdout)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			enc.Encode(rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: -32700, Message: "parse error: " + err.Error()}})
+			continue
+		}
+
+		result, err := s.handleRPC(req)
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+		if err != nil {
+			resp.Error = &rpcError{Code: -32000, Message: err.Error()}
+		} else {
+			resp.Result = result
+		}
+		enc.Encode(resp)
+	}
+}
+
+func (s *replSession) handleRPC(req rpcRequest) (string, error) {
+	switch req.Method {
+	case "edit":
+		var p editParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return "", fmt.Errorf("invalid params: %v", err)
+		}
+		return "", s.edit(p.StartByte, p.OldEndByte, p.NewEndByte, p.Text)
+	case "reparse":
+		s.reparse()
+		return "", nil
+	case "print":
+		var sb strings.Builder
+		printTree(&sb, s.tree.RootNode(), s.source, 0)
+		return strings.TrimRight(sb.String(), "\n"), nil
+	case "query":
+		var p queryParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return "", fmt.Errorf("invalid params: %v", err)
+		}
+		var sb strings.Builder
+		if err := executeQuery(&sb, s.tree, s.language, s.source, p.Pattern); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(sb.String(), "\n"), nil
+	case "node":
+		var p nodeParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return "", fmt.Errorf("invalid params: %v", err)
+		}
+		return s.handleNodeLine(fmt.Sprintf("%d %d", p.Row, p.Col))
+	default:
+		return "", fmt.Errorf("unknown method %q", req.Method)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+)
+
+func newReplSession(t *testing.T, source []byte) *replSession {
+	t.Helper()
+
+	language := sitter.NewLanguage(tree_sitter_go.Language())
+	parser := sitter.NewParser()
+	t.Cleanup(parser.Close)
+	if err := parser.SetLanguage(language); err != nil {
+		t.Fatalf("SetLanguage: %v", err)
+	}
+	tree := parser.Parse(source, nil)
+	s := &replSession{parser: parser, language: language, tree: tree, source: source}
+	t.Cleanup(func() { s.tree.Close() })
+
+	return s
+}
+
+func TestPointForByte(t *testing.T) {
+	source := []byte("ab\ncd\nef")
+
+	cases := []struct {
+		offset uint
+		row    uint
+		col    uint
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{3, 1, 0},
+		{4, 1, 1},
+		{6, 2, 0},
+	}
+
+	for _, c := range cases {
+		p := pointForByte(source, c.offset)
+		if p.Row != c.row || p.Column != c.col {
+			t.Errorf("pointForByte(%d) = %d:%d, want %d:%d", c.offset, p.Row, p.Column, c.row, c.col)
+		}
+	}
+}
+
+func TestReplSessionEditAndReparse(t *testing.T) {
+	source := []byte("package main\n\nfunc main() {}\n")
+	s := newReplSession(t, source)
+
+	// Replace "main" (the function name) with "run".
+	idx := strings.Index(string(source), "func ") + len("func ")
+	oldEnd := idx + len("main")
+
+	if err := s.edit(uint(idx), uint(oldEnd), uint(idx+len("run")), "run"); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	s.reparse()
+
+	if !strings.Contains(string(s.source), "func run()") {
+		t.Errorf("source after edit+reparse = %q, want it to contain \"func run()\"", s.source)
+	}
+
+	var found bool
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if n.Kind() == "identifier" {
+			text := s.source[n.StartByte():n.EndByte()]
+			if string(text) == "run" {
+				found = true
+			}
+		}
+		for i := uint(0); i < n.ChildCount(); i++ {
+			walk(n.Child(i))
+		}
+	}
+	walk(s.tree.RootNode())
+	if !found {
+		t.Error("reparsed tree has no \"run\" identifier node")
+	}
+}
+
+func TestReplSessionEditOutOfBounds(t *testing.T) {
+	s := newReplSession(t, []byte("short"))
+	if err := s.edit(0, 100, 100, "x"); err == nil {
+		t.Error("edit with an out-of-bounds range: want error, got nil")
+	}
+}
+
+func TestReplSessionEditTextLengthMismatch(t *testing.T) {
+	s := newReplSession(t, []byte("short"))
+	if err := s.edit(0, 1, 3, "x"); err == nil {
+		t.Error("edit with len(text) != new_end_byte-start_byte: want error, got nil")
+	}
+}
+
+func TestReplSessionDispatchPrintAndNode(t *testing.T) {
+	s := newReplSession(t, []byte("package main\n\nfunc main() {}\n"))
+
+	out, err := s.dispatch("print")
+	if err != nil {
+		t.Fatalf("dispatch(print): %v", err)
+	}
+	if !strings.Contains(out, "source_file") {
+		t.Errorf("dispatch(print) output = %q, want it to mention source_file", out)
+	}
+
+	out, err = s.dispatch("node 2 5")
+	if err != nil {
+		t.Fatalf("dispatch(node 2 5): %v", err)
+	}
+	if !strings.Contains(out, "identifier") {
+		t.Errorf("dispatch(node 2 5) = %q, want it to name the identifier node", out)
+	}
+}
+
+func TestReplSessionDispatchUnknownCommand(t *testing.T) {
+	s := newReplSession(t, []byte("package main\n"))
+	if _, err := s.dispatch("bogus"); err == nil {
+		t.Error("dispatch(bogus): want error, got nil")
+	}
+}
+
+func TestReplSessionHandleRPCEdit(t *testing.T) {
+	source := []byte("package main\n\nfunc main() {}\n")
+	s := newReplSession(t, source)
+
+	idx := strings.Index(string(source), "func ") + len("func ")
+	oldEnd := idx + len("main")
+	params := `{"start_byte":` + strconv.Itoa(idx) + `,"old_end_byte":` + strconv.Itoa(oldEnd) + `,"new_end_byte":` + strconv.Itoa(idx+3) + `,"text":"run"}`
+
+	_, err := s.handleRPC(rpcRequest{Method: "edit", Params: []byte(params)})
+	if err != nil {
+		t.Fatalf("handleRPC(edit): %v", err)
+	}
+	if !strings.Contains(string(s.source), "func run(") {
+		t.Errorf("source after RPC edit = %q, want it to contain \"func run(\"", s.source)
+	}
+}
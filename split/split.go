@@ -0,0 +1,261 @@
+// Package split turns a parsed tree-sitter tree into semantically coherent
+// chunks sized to fit an LLM context window, following the chunking
+// strategy popularized by splitter-tree-sitter: whole syntax subtrees are
+// kept together whenever they fit the budget, and only split further when
+// they don't.
+package split
+
+import (
+	"bytes"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Counter measures the "size" of a slice of source text against a budget.
+// The default counter (used when Options.Counter is nil) counts bytes. A
+// caller that wants to budget by characters or model tokens supplies their
+// own counter along with a matching Max.
+type Counter func(text []byte) int
+
+// Options controls how Split partitions a tree.
+type Options struct {
+	// Max is the size budget a chunk must fit within, measured by Counter
+	// (or by raw byte length if Counter is nil).
+	Max int
+
+	// Counter measures chunk size. Defaults to byte length.
+	Counter Counter
+
+	// Overlap duplicates the last N bytes of the previous chunk at the
+	// start of the next chunk's Text, a common trick in RAG pipelines to
+	// preserve context across a chunk boundary. It does not affect
+	// StartByte/EndByte, which always describe the chunk's own node range.
+	Overlap int
+}
+
+func (o Options) size(text []byte) int {
+	if o.Counter != nil {
+		return o.Counter(text)
+	}
+	return len(text)
+}
+
+// Chunk is one emitted piece of source, corresponding to either a single
+// syntax subtree or a line-based fallback slice when no subtree fit the
+// budget.
+type Chunk struct {
+	// Kind is the node kind of the chunk's root (e.g. "function_declaration").
+	// For line-based fallback chunks this is the kind of the oversized node
+	// they were split out of.
+	Kind string
+
+	// Path is the chain of ancestor kinds down to Kind, joined by ">", e.g.
+	// "source_file>function_declaration>block".
+	Path string
+
+	StartByte uint
+	EndByte   uint
+	StartRow  uint
+	StartCol  uint
+	EndRow    uint
+	EndCol    uint
+
+	// Text is the chunk's content, including any requested overlap from the
+	// previous chunk.
+	Text []byte
+}
+
+// Split walks root depth-first and returns chunks that each fit within
+// opts.Max, preferring to keep whole subtrees together. See the package
+// doc for the algorithm.
+func Split(root *sitter.Node, source []byte, opts Options) []Chunk {
+	chunks := splitNode(root, source, opts, "")
+	return applyOverlap(chunks, opts.Overlap)
+}
+
+func splitNode(node *sitter.Node, source []byte, opts Options, path string) []Chunk {
+	span := source[node.StartByte():node.EndByte()]
+	if opts.size(span) <= opts.Max {
+		return []Chunk{chunkOf(node, source, path)}
+	}
+
+	childPath := appendPath(path, node.Kind())
+
+	childCount := node.ChildCount()
+	if childCount == 0 {
+		// A single leaf/identifier that still exceeds the budget: there is
+		// nothing left to recurse into, so fall back to a line-based split
+		// of its own source slice.
+		return lineSplit(node, source, opts, path)
+	}
+
+	var chunks []Chunk
+	var buf *bufferedRange
+
+	flush := func() {
+		if buf == nil {
+			return
+		}
+		chunks = append(chunks, Chunk{
+			Kind:      node.Kind(),
+			Path:      path,
+			StartByte: buf.start,
+			EndByte:   buf.end,
+			StartRow:  buf.startRow,
+			StartCol:  buf.startCol,
+			EndRow:    buf.endRow,
+			EndCol:    buf.endCol,
+			Text:      source[buf.start:buf.end],
+		})
+		buf = nil
+	}
+
+	for i := uint(0); i < childCount; i++ {
+		child := node.Child(i)
+		childSpan := source[child.StartByte():child.EndByte()]
+
+		if buf != nil && opts.size(source[buf.start:child.EndByte()]) > opts.Max {
+			flush()
+		}
+
+		if opts.size(childSpan) <= opts.Max {
+			if buf == nil {
+				buf = newBufferedRange(child)
+			} else {
+				buf.extend(child)
+			}
+			continue
+		}
+
+		// The child itself doesn't fit; flush whatever's buffered and
+		// recurse into the child on its own.
+		flush()
+		chunks = append(chunks, splitNode(child, source, opts, childPath)...)
+	}
+	flush()
+
+	return chunks
+}
+
+// bufferedRange tracks the running span of sibling subtrees accumulated
+// while walking a node's children.
+type bufferedRange struct {
+	start, end         uint
+	startRow, startCol uint
+	endRow, endCol     uint
+}
+
+func newBufferedRange(node *sitter.Node) *bufferedRange {
+	start, end := node.StartPosition(), node.EndPosition()
+	return &bufferedRange{
+		start: node.StartByte(), end: node.EndByte(),
+		startRow: start.Row, startCol: start.Column,
+		endRow: end.Row, endCol: end.Column,
+	}
+}
+
+func (b *bufferedRange) extend(node *sitter.Node) {
+	b.end = node.EndByte()
+	end := node.EndPosition()
+	b.endRow, b.endCol = end.Row, end.Column
+}
+
+func chunkOf(node *sitter.Node, source []byte, path string) Chunk {
+	start, end := node.StartPosition(), node.EndPosition()
+	return Chunk{
+		Kind:      node.Kind(),
+		Path:      path,
+		StartByte: node.StartByte(),
+		EndByte:   node.EndByte(),
+		StartRow:  start.Row,
+		StartCol:  start.Column,
+		EndRow:    end.Row,
+		EndCol:    end.Column,
+		Text:      source[node.StartByte():node.EndByte()],
+	}
+}
+
+func appendPath(path, kind string) string {
+	if path == "" {
+		return kind
+	}
+	return path + ">" + kind
+}
+
+// lineSplit breaks node's raw source slice into line-based chunks, each
+// within opts.Max, for the rare case where a single leaf node is too large
+// to fit on its own (e.g. a minified one-line file, or a giant string
+// literal).
+func lineSplit(node *sitter.Node, source []byte, opts Options, path string) []Chunk {
+	text := source[node.StartByte():node.EndByte()]
+	baseByte := node.StartByte()
+	endPos := node.EndPosition()
+
+	type line struct {
+		startOff, endOff   int
+		startRow, startCol uint
+		endRow, endCol     uint
+	}
+
+	var lines []line
+	offset := 0
+	row, col := node.StartPosition().Row, node.StartPosition().Column
+	for offset < len(text) {
+		var lineEnd int
+		var nextRow, nextCol uint
+		if nl := bytes.IndexByte(text[offset:], '\n'); nl == -1 {
+			lineEnd = len(text)
+			nextRow, nextCol = endPos.Row, endPos.Column
+		} else {
+			lineEnd = offset + nl + 1
+			nextRow, nextCol = row+1, 0
+		}
+		lines = append(lines, line{
+			startOff: offset, endOff: lineEnd,
+			startRow: row, startCol: col,
+			endRow: nextRow, endCol: nextCol,
+		})
+		offset = lineEnd
+		row, col = nextRow, nextCol
+	}
+
+	var chunks []Chunk
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && opts.size(text[lines[i].startOff:lines[j+1].endOff]) <= opts.Max {
+			j++
+		}
+		chunks = append(chunks, Chunk{
+			Kind:      node.Kind(),
+			Path:      path,
+			StartByte: baseByte + uint(lines[i].startOff),
+			EndByte:   baseByte + uint(lines[j].endOff),
+			StartRow:  lines[i].startRow,
+			StartCol:  lines[i].startCol,
+			EndRow:    lines[j].endRow,
+			EndCol:    lines[j].endCol,
+			Text:      text[lines[i].startOff:lines[j].endOff],
+		})
+		i = j + 1
+	}
+
+	return chunks
+}
+
+func applyOverlap(chunks []Chunk, overlap int) []Chunk {
+	if overlap <= 0 {
+		return chunks
+	}
+	for i := 1; i < len(chunks); i++ {
+		prev := chunks[i-1].Text
+		if len(prev) == 0 {
+			continue
+		}
+		n := overlap
+		if n > len(prev) {
+			n = len(prev)
+		}
+		chunks[i].Text = append(append([]byte{}, prev[len(prev)-n:]...), chunks[i].Text...)
+	}
+	return chunks
+}
@@ -0,0 +1,98 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+)
+
+func parseGo(t *testing.T, source []byte) *sitter.Node {
+	t.Helper()
+
+	language := sitter.NewLanguage(tree_sitter_go.Language())
+	parser := sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(language); err != nil {
+		t.Fatalf("SetLanguage: %v", err)
+	}
+
+	tree := parser.Parse(source, nil)
+	t.Cleanup(tree.Close)
+	return tree.RootNode()
+}
+
+// A small file well within the default-sized budget should come back as a
+// single chunk whose Text is the whole source, not empty. This is the
+// "whole subtree fits the budget" path and the one almost every real input
+// hits.
+func TestSplitWholeFileFitsBudget(t *testing.T) {
+	source := []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")
+	root := parseGo(t, source)
+
+	chunks := Split(root, source, Options{Max: 1024})
+	if len(chunks) != 1 {
+		t.Fatalf("got %d chunks, want 1", len(chunks))
+	}
+
+	got := string(chunks[0].Text)
+	if got != string(source) {
+		t.Errorf("chunk Text = %q, want %q", got, string(source))
+	}
+}
+
+func TestSplitRecursesWhenOverBudget(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("package main\n\n")
+	for i := 0; i < 20; i++ {
+		sb.WriteString("func f")
+		sb.WriteString(strings.Repeat("x", i))
+		sb.WriteString("() {\n\tprintln(\"hello world this is a line of code\")\n}\n\n")
+	}
+	source := []byte(sb.String())
+	root := parseGo(t, source)
+
+	chunks := Split(root, source, Options{Max: 80})
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want more than 1 for an oversized file", len(chunks))
+	}
+
+	for i, c := range chunks {
+		if len(c.Text) == 0 {
+			t.Errorf("chunk %d has empty Text", i)
+		}
+		if string(source[c.StartByte:c.EndByte]) == "" {
+			continue
+		}
+	}
+}
+
+func TestSplitOverlapPrependsPreviousTail(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("package main\n\n")
+	for i := 0; i < 5; i++ {
+		sb.WriteString("func f")
+		sb.WriteString(strings.Repeat("x", i))
+		sb.WriteString("() {\n\tprintln(\"hello\")\n}\n\n")
+	}
+	source := []byte(sb.String())
+	root := parseGo(t, source)
+
+	chunks := Split(root, source, Options{Max: 60, Overlap: 10})
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want more than 1", len(chunks))
+	}
+
+	for i := 1; i < len(chunks); i++ {
+		prevText := chunks[i-1].Text
+		n := 10
+		if n > len(prevText) {
+			n = len(prevText)
+		}
+		want := prevText[len(prevText)-n:]
+		if !strings.HasPrefix(string(chunks[i].Text), string(want)) {
+			t.Errorf("chunk %d Text does not start with previous chunk's overlap tail", i)
+		}
+	}
+}